@@ -0,0 +1,86 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Account is a brokerage or IIS account returned by Accounts.
+type Account struct {
+	BrokerAccountType BrokerAccountType `json:"brokerAccountType"`
+	BrokerAccountID   string            `json:"brokerAccountId"`
+}
+
+// Accounts lists every account available to the authenticated user. Most
+// users have a single brokerage account, but those with an IIS will see it
+// listed alongside it; pass the desired BrokerAccountID to calls that accept
+// WithAccount to target one specifically.
+func (c *TradingClient) Accounts(ctx context.Context) ([]Account, error) {
+	path := c.apiURL + "/user/accounts"
+
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := c.doRequest(req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	type response struct {
+		Payload struct {
+			Accounts []Account `json:"accounts"`
+		} `json:"payload"`
+	}
+
+	var resp response
+	if err = json.Unmarshal(respBody, &resp); err != nil {
+		return nil, errors.Wrapf(err, "can't unmarshal response to %s, respBody=%s", path, respBody)
+	}
+
+	return resp.Payload.Accounts, nil
+}
+
+// CallOption customizes a single TradingClient call.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	brokerAccountID string
+}
+
+// WithAccount targets a specific brokerage/IIS account for a call. Without
+// it, calls fall back to the user's default account.
+func WithAccount(brokerAccountID string) CallOption {
+	return func(o *callOptions) {
+		o.brokerAccountID = brokerAccountID
+	}
+}
+
+func applyCallOptions(opts []CallOption) callOptions {
+	var o callOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// withBrokerAccount appends the brokerAccountId query parameter to path when
+// opts named one, leaving path untouched otherwise.
+func withBrokerAccount(path string, opts callOptions) string {
+	if opts.brokerAccountID == "" {
+		return path
+	}
+
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+
+	return path + sep + "brokerAccountId=" + url.QueryEscape(opts.brokerAccountID)
+}
@@ -0,0 +1,126 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CandleInterval is the granularity requested from the candles endpoint.
+type CandleInterval string
+
+const (
+	CandleInterval1Min  CandleInterval = "1min"
+	CandleInterval5Min  CandleInterval = "5min"
+	CandleInterval15Min CandleInterval = "15min"
+	CandleInterval30Min CandleInterval = "30min"
+	CandleIntervalHour  CandleInterval = "hour"
+	CandleIntervalDay   CandleInterval = "day"
+	CandleIntervalWeek  CandleInterval = "week"
+	CandleIntervalMonth CandleInterval = "month"
+)
+
+// maxCandlesRange is the widest [from, to) window the API accepts in a
+// single request for a given interval.
+var maxCandlesRange = map[CandleInterval]time.Duration{
+	CandleInterval1Min:  24 * time.Hour,
+	CandleInterval5Min:  24 * time.Hour,
+	CandleInterval15Min: 24 * time.Hour,
+	CandleInterval30Min: 24 * time.Hour,
+	CandleIntervalHour:  7 * 24 * time.Hour,
+	CandleIntervalDay:   365 * 24 * time.Hour,
+	CandleIntervalWeek:  2 * 365 * 24 * time.Hour,
+	CandleIntervalMonth: 10 * 365 * 24 * time.Hour,
+}
+
+// Candle is a single OHLCV bar.
+type Candle struct {
+	FIGI     string         `json:"figi"`
+	Interval CandleInterval `json:"interval"`
+	Open     float64        `json:"o"`
+	Close    float64        `json:"c"`
+	High     float64        `json:"h"`
+	Low      float64        `json:"l"`
+	Volume   float64        `json:"v"`
+	Time     time.Time      `json:"time"`
+}
+
+// Candles fetches historical candles for figi over [from, to]. The API caps
+// how wide a range it accepts per interval, so this transparently splits
+// [from, to] into permitted windows, issues them in order, and stitches the
+// results into a single chronological, deduplicated slice.
+func (c *TradingClient) Candles(ctx context.Context, figi string, from, to time.Time, interval CandleInterval) ([]Candle, error) {
+	windowSize, ok := maxCandlesRange[interval]
+	if !ok {
+		return nil, errors.Errorf("unknown candle interval %q", interval)
+	}
+
+	seen := make(map[int64]struct{})
+	var candles []Candle
+
+	for windowFrom := from; windowFrom.Before(to); windowFrom = windowFrom.Add(windowSize) {
+		windowTo := windowFrom.Add(windowSize)
+		if windowTo.After(to) {
+			windowTo = to
+		}
+
+		chunk, err := c.candles(ctx, figi, windowFrom, windowTo, interval)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, candle := range chunk {
+			key := candle.Time.Unix()
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			candles = append(candles, candle)
+		}
+	}
+
+	sort.Slice(candles, func(i, j int) bool {
+		return candles[i].Time.Before(candles[j].Time)
+	})
+
+	return candles, nil
+}
+
+func (c *TradingClient) candles(ctx context.Context, figi string, from, to time.Time, interval CandleInterval) ([]Candle, error) {
+	q := url.Values{
+		"figi":     []string{figi},
+		"from":     []string{from.Format(time.RFC3339)},
+		"to":       []string{to.Format(time.RFC3339)},
+		"interval": []string{string(interval)},
+	}
+
+	path := c.apiURL + "/market/candles?" + q.Encode()
+
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := c.doRequest(req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	type response struct {
+		Payload struct {
+			Candles []Candle `json:"candles"`
+		} `json:"payload"`
+	}
+
+	var resp response
+	if err = json.Unmarshal(respBody, &resp); err != nil {
+		return nil, errors.Wrapf(err, "can't unmarshal response to %s, respBody=%s", path, respBody)
+	}
+
+	return resp.Payload.Candles, nil
+}
@@ -0,0 +1,74 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCandlesSplitsRangeDedupsAndSorts(t *testing.T) {
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(48 * time.Hour)
+	boundary := from.Add(24 * time.Hour)
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		q := r.URL.Query()
+		windowFrom, err := time.Parse(time.RFC3339, q.Get("from"))
+		if err != nil {
+			t.Fatalf("can't parse from=%s: %v", q.Get("from"), err)
+		}
+
+		var candles []Candle
+		if windowFrom.Equal(from) {
+			// First window: out-of-order on purpose, plus the boundary candle
+			// that the second window will also return.
+			candles = []Candle{
+				{Time: boundary.Add(-time.Minute)},
+				{Time: from},
+				{Time: boundary},
+			}
+		} else {
+			candles = []Candle{
+				{Time: boundary},
+				{Time: boundary.Add(time.Minute)},
+			}
+		}
+
+		resp := struct {
+			Payload struct {
+				Candles []Candle `json:"candles"`
+			} `json:"payload"`
+		}{}
+		resp.Payload.Candles = candles
+
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	c := NewTradingClientCustom("token", srv.URL, WithHTTPClient(srv.Client()))
+
+	candles, err := c.Candles(context.Background(), "FIGI", from, to, CandleInterval1Min)
+	if err != nil {
+		t.Fatalf("Candles() error = %v", err)
+	}
+
+	if requests != 2 {
+		t.Fatalf("got %d requests, want 2 (one per 24h window)", requests)
+	}
+
+	want := []time.Time{from, boundary.Add(-time.Minute), boundary, boundary.Add(time.Minute)}
+	if len(candles) != len(want) {
+		t.Fatalf("got %d candles, want %d: %+v", len(candles), len(want), candles)
+	}
+	for i, candle := range candles {
+		if !candle.Time.Equal(want[i]) {
+			t.Errorf("candles[%d].Time = %v, want %v", i, candle.Time, want[i])
+		}
+	}
+}
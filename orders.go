@@ -0,0 +1,142 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TimeInForce expresses how long a submitted order should remain active.
+type TimeInForce string
+
+const (
+	GTC TimeInForce = "GTC" // Good-Till-Canceled
+	GTT TimeInForce = "GTT" // Good-Till-Time
+	IOC TimeInForce = "IOC" // Immediate-Or-Cancel
+	FOK TimeInForce = "FOK" // Fill-Or-Kill
+)
+
+func (t TimeInForce) Valid() bool {
+	switch t {
+	case GTC, GTT, IOC, FOK:
+		return true
+	default:
+		return false
+	}
+}
+
+// PlacedMarketOrder is the result of submitting a market order.
+type PlacedMarketOrder struct {
+	OrderID       string        `json:"orderId"`
+	Operation     OperationType `json:"operation"`
+	Status        OrderStatus   `json:"status"`
+	RejectReason  string        `json:"rejectReason,omitempty"`
+	RequestedLots int           `json:"requestedLots"`
+	ExecutedLots  int           `json:"executedLots"`
+	Commission    MoneyAmount   `json:"commission"`
+}
+
+// PlacedStopOrder is the result of submitting a stop order.
+type PlacedStopOrder struct {
+	OrderID        string        `json:"orderId"`
+	Operation      OperationType `json:"operation"`
+	Status         OrderStatus   `json:"status"`
+	RejectReason   string        `json:"rejectReason,omitempty"`
+	RequestedLots  int           `json:"requestedLots"`
+	TriggerPrice   float64       `json:"triggerPrice"`
+	TimeInForce    TimeInForce   `json:"timeInForce"`
+	ExpirationTime *time.Time    `json:"expirationTime,omitempty"`
+}
+
+func (c *TradingClient) MarketOrder(ctx context.Context, figi string, lots int, operation OperationType, opts ...CallOption) (PlacedMarketOrder, error) {
+	path := withBrokerAccount(c.apiURL+"/orders/market-order?figi="+figi, applyCallOptions(opts))
+
+	payload := struct {
+		Lots      int           `json:"lots"`
+		Operation OperationType `json:"operation"`
+	}{Lots: lots, Operation: operation}
+
+	bb, err := json.Marshal(payload)
+	if err != nil {
+		return PlacedMarketOrder{}, errors.Errorf("can't marshal request to %s body=%+v", path, payload)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, path, bytes.NewReader(bb))
+	if err != nil {
+		return PlacedMarketOrder{}, err
+	}
+
+	respBody, err := c.doRequest(req, false)
+	if err != nil {
+		return PlacedMarketOrder{}, err
+	}
+
+	type response struct {
+		Payload PlacedMarketOrder `json:"payload"`
+	}
+
+	var resp response
+	if err = json.Unmarshal(respBody, &resp); err != nil {
+		return PlacedMarketOrder{}, errors.Wrapf(err, "can't unmarshal response to %s, respBody=%s", path, respBody)
+	}
+
+	return resp.Payload, nil
+}
+
+// StopOrder submits a stop-loss/take-profit order that triggers once the
+// instrument trades at triggerPrice. timeInForce controls how long the
+// order lives before it's dropped; expiration is only meaningful for GTT
+// and is ignored otherwise.
+func (c *TradingClient) StopOrder(ctx context.Context, figi string, lots int, operation OperationType, triggerPrice float64, timeInForce TimeInForce, expiration time.Time) (PlacedStopOrder, error) {
+	if !timeInForce.Valid() {
+		return PlacedStopOrder{}, errors.Errorf("invalid time in force %q", timeInForce)
+	}
+
+	path := c.apiURL + "/stop-orders?figi=" + figi
+
+	payload := struct {
+		Lots           int           `json:"lots"`
+		Operation      OperationType `json:"operation"`
+		TriggerPrice   float64       `json:"triggerPrice"`
+		TimeInForce    TimeInForce   `json:"timeInForce"`
+		ExpirationTime *time.Time    `json:"expirationTime,omitempty"`
+	}{
+		Lots:         lots,
+		Operation:    operation,
+		TriggerPrice: triggerPrice,
+		TimeInForce:  timeInForce,
+	}
+	if timeInForce == GTT {
+		payload.ExpirationTime = &expiration
+	}
+
+	bb, err := json.Marshal(payload)
+	if err != nil {
+		return PlacedStopOrder{}, errors.Errorf("can't marshal request to %s body=%+v", path, payload)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, path, bytes.NewReader(bb))
+	if err != nil {
+		return PlacedStopOrder{}, err
+	}
+
+	respBody, err := c.doRequest(req, false)
+	if err != nil {
+		return PlacedStopOrder{}, err
+	}
+
+	type response struct {
+		Payload PlacedStopOrder `json:"payload"`
+	}
+
+	var resp response
+	if err = json.Unmarshal(respBody, &resp); err != nil {
+		return PlacedStopOrder{}, errors.Wrapf(err, "can't unmarshal response to %s, respBody=%s", path, respBody)
+	}
+
+	return resp.Payload, nil
+}
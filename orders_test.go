@@ -0,0 +1,23 @@
+package sdk
+
+import "testing"
+
+func TestTimeInForceValid(t *testing.T) {
+	cases := []struct {
+		tif  TimeInForce
+		want bool
+	}{
+		{GTC, true},
+		{GTT, true},
+		{IOC, true},
+		{FOK, true},
+		{TimeInForce("bogus"), false},
+		{TimeInForce(""), false},
+	}
+
+	for _, tt := range cases {
+		if got := tt.tif.Valid(); got != tt.want {
+			t.Errorf("TimeInForce(%q).Valid() = %v, want %v", tt.tif, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package sdk
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryPolicy controls how TradingClient retries failed requests. The zero
+// value disables retries entirely.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of tries, including the first one.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry; subsequent retries back
+	// off exponentially from it. Jitter picks the actual delay between half
+	// and all of the computed backoff.
+	BaseDelay time.Duration
+	// MaxDelay caps the computed backoff, regardless of attempt count.
+	MaxDelay time.Duration
+	// RetryableStatus lists the HTTP status codes worth retrying.
+	RetryableStatus map[int]bool
+}
+
+// DefaultRetryPolicy retries server errors and 429s a handful of times with
+// jittered exponential backoff.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		RetryableStatus: map[int]bool{
+			http.StatusTooManyRequests:     true,
+			http.StatusInternalServerError: true,
+			http.StatusBadGateway:          true,
+			http.StatusServiceUnavailable:  true,
+			http.StatusGatewayTimeout:      true,
+		},
+	}
+}
+
+// retryable reports whether a response with the given status is worth
+// retrying. status == 0 stands for a transport-level failure (DNS error,
+// dial timeout, connection reset, ...), which is retryable regardless of
+// RetryableStatus since no HTTP response was ever received.
+func (p RetryPolicy) retryable(status int) bool {
+	if status == 0 {
+		return true
+	}
+	return p.RetryableStatus[status]
+}
+
+// delay picks the backoff before the given (zero-based) retry attempt,
+// honoring retryAfter when the server provided one.
+func (p RetryPolicy) delay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	d := p.BaseDelay * time.Duration(1<<uint(attempt))
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+
+	return d/2 + time.Duration(rand.Int63n(int64(d)/2+1))
+}
+
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(header); err == nil {
+		return time.Until(when)
+	}
+	return 0
+}
+
+// RateLimiter is a token bucket shared across goroutines so that concurrent
+// callers don't collectively exceed the API's per-endpoint rate limits.
+type RateLimiter struct {
+	mu     sync.Mutex
+	tokens float64
+	max    float64
+	refill float64 // tokens added per second
+	last   time.Time
+}
+
+// NewRateLimiter creates a limiter allowing ratePerSecond requests per
+// second on average, with room for a burst of up to burst requests.
+func NewRateLimiter(ratePerSecond float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		tokens: float64(burst),
+		max:    float64(burst),
+		refill: ratePerSecond,
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done.
+func (r *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		wait := r.take()
+		if wait <= 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (r *RateLimiter) take() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.tokens = math.Min(r.max, r.tokens+now.Sub(r.last).Seconds()*r.refill)
+	r.last = now
+
+	if r.tokens >= 1 {
+		r.tokens--
+		return 0
+	}
+
+	if r.refill <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+
+	return time.Duration((1 - r.tokens) / r.refill * float64(time.Second))
+}
+
+// ErrRateLimited is returned when a request's final attempt (after any
+// retries; requests that aren't retried have only one) gets 429 Too Many
+// Requests with a TradingError body. It wraps the underlying
+// TradingError so callers can still inspect TrackingID/Code programmatically.
+type ErrRateLimited struct {
+	TradingError
+}
+
+func (e *ErrRateLimited) Error() string {
+	return "rate limited: " + e.TradingError.Error()
+}
+
+func (e *ErrRateLimited) Unwrap() error {
+	return e.TradingError
+}
+
+var _ error = (*ErrRateLimited)(nil)
@@ -0,0 +1,78 @@
+package sdk
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyRetryable(t *testing.T) {
+	p := DefaultRetryPolicy()
+
+	cases := []struct {
+		status int
+		want   bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusBadRequest, false},
+		{0, true}, // transport-level failure: no response was ever received
+	}
+
+	for _, tt := range cases {
+		if got := p.retryable(tt.status); got != tt.want {
+			t.Errorf("retryable(%d) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	if got := p.delay(0, 5*time.Second); got != 5*time.Second {
+		t.Errorf("delay with retryAfter set = %v, want 5s", got)
+	}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		d := p.delay(attempt, 0)
+		if d <= 0 || d > p.MaxDelay {
+			t.Errorf("delay(%d, 0) = %v, want in (0, %v]", attempt, d, p.MaxDelay)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := parseRetryAfter(""); got != 0 {
+		t.Errorf("parseRetryAfter(\"\") = %v, want 0", got)
+	}
+	if got := parseRetryAfter("5"); got != 5*time.Second {
+		t.Errorf("parseRetryAfter(\"5\") = %v, want 5s", got)
+	}
+	if got := parseRetryAfter("not-a-date"); got != 0 {
+		t.Errorf("parseRetryAfter(garbage) = %v, want 0", got)
+	}
+}
+
+func TestRateLimiterTakeWaitsOnceBurstExhausted(t *testing.T) {
+	r := NewRateLimiter(10, 1)
+
+	if wait := r.take(); wait != 0 {
+		t.Fatalf("first take() = %v, want 0 (burst token available)", wait)
+	}
+
+	if wait := r.take(); wait <= 0 {
+		t.Fatalf("second take() = %v, want > 0 (burst exhausted)", wait)
+	}
+}
+
+func TestRateLimiterTakeZeroRefillDoesNotDivideByZero(t *testing.T) {
+	r := NewRateLimiter(0, 1)
+
+	r.take() // drains the single burst token
+
+	wait := r.take()
+	if wait <= 0 {
+		t.Fatalf("take() with zero refill = %v, want a large positive wait", wait)
+	}
+}
@@ -0,0 +1,111 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+const SandboxApiURL = "https://api-invest.tinkoff.ru/openapi/sandbox"
+
+// BrokerAccountType selects which kind of account Register creates.
+type BrokerAccountType string
+
+const (
+	BrokerAccountTinkoff BrokerAccountType = "Tinkoff"
+	BrokerAccountIIS     BrokerAccountType = "TinkoffIis"
+)
+
+// RegisteredAccount is returned by Register.
+type RegisteredAccount struct {
+	BrokerAccountType BrokerAccountType `json:"brokerAccountType"`
+	BrokerAccountID   string            `json:"brokerAccountId"`
+}
+
+// SandboxClient is a TradingClient pointed at the sandbox environment, with
+// extra endpoints for seeding and resetting sandbox accounts. Everything
+// TradingClient can do (placing orders, reading the portfolio, ...) works
+// the same way here, against fake money.
+type SandboxClient struct {
+	*TradingClient
+}
+
+func NewSandboxClient(token string, opts ...Option) *SandboxClient {
+	return &SandboxClient{TradingClient: NewTradingClientCustom(token, SandboxApiURL, opts...)}
+}
+
+// Register creates a new sandbox account of the given type.
+func (c *SandboxClient) Register(ctx context.Context, accountType BrokerAccountType) (RegisteredAccount, error) {
+	path := c.apiURL + "/register"
+
+	payload := struct {
+		BrokerAccountType BrokerAccountType `json:"brokerAccountType,omitempty"`
+	}{BrokerAccountType: accountType}
+
+	bb, err := json.Marshal(payload)
+	if err != nil {
+		return RegisteredAccount{}, errors.Errorf("can't marshal request to %s body=%+v", path, payload)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, path, bytes.NewReader(bb))
+	if err != nil {
+		return RegisteredAccount{}, err
+	}
+
+	respBody, err := c.doRequest(req, false)
+	if err != nil {
+		return RegisteredAccount{}, err
+	}
+
+	type response struct {
+		Payload RegisteredAccount `json:"payload"`
+	}
+
+	var resp response
+	if err = json.Unmarshal(respBody, &resp); err != nil {
+		return RegisteredAccount{}, errors.Wrapf(err, "can't unmarshal response to %s, respBody=%s", path, respBody)
+	}
+
+	return resp.Payload, nil
+}
+
+// SetCurrencyBalance overwrites the sandbox account's balance for currency.
+func (c *SandboxClient) SetCurrencyBalance(ctx context.Context, currency Currency, balance float64) error {
+	path := c.apiURL + "/currencies/balance"
+
+	payload := struct {
+		Currency Currency `json:"currency"`
+		Balance  float64  `json:"balance"`
+	}{Currency: currency, Balance: balance}
+
+	return c.postJSONThrow(ctx, path, payload, false)
+}
+
+// SetPositionBalance overwrites the sandbox account's lot balance for figi.
+func (c *SandboxClient) SetPositionBalance(ctx context.Context, figi string, balance float64) error {
+	path := c.apiURL + "/positions/balance"
+
+	payload := struct {
+		FIGI    string  `json:"figi"`
+		Balance float64 `json:"balance"`
+	}{FIGI: figi, Balance: balance}
+
+	return c.postJSONThrow(ctx, path, payload, false)
+}
+
+// RemoveAccount deletes a sandbox account entirely.
+func (c *SandboxClient) RemoveAccount(ctx context.Context, accountID string) error {
+	path := c.apiURL + "/remove?brokerAccountId=" + accountID
+
+	return c.postJSONThrow(ctx, path, nil, false)
+}
+
+// Clear resets the sandbox account's positions and balances back to zero.
+func (c *SandboxClient) Clear(ctx context.Context) error {
+	path := c.apiURL + "/clear"
+
+	return c.postJSONThrow(ctx, path, nil, false)
+}
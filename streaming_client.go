@@ -0,0 +1,340 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+)
+
+const StreamingApiURL = "wss://api-invest.tinkoff.ru/openapi/md/v1/md-openapi/ws"
+
+// pingPeriod is how often the client pings the server. A connection that
+// delivers nothing, not even a pong, for twice this long is treated as dropped.
+const pingPeriod = 15 * time.Second
+
+type EventType string
+
+const (
+	CandleEventType         EventType = "candle"
+	OrderbookEventType      EventType = "orderbook"
+	InstrumentInfoEventType EventType = "instrument_info"
+)
+
+// CandleEvent is a single candle update for a subscribed FIGI/interval pair.
+type CandleEvent struct {
+	FIGI     string         `json:"figi"`
+	Interval CandleInterval `json:"interval"`
+	Open     float64        `json:"o"`
+	Close    float64        `json:"c"`
+	High     float64        `json:"h"`
+	Low      float64        `json:"l"`
+	Volume   float64        `json:"v"`
+	Time     time.Time      `json:"time"`
+}
+
+// OrderbookEvent is a snapshot of the order book for a subscribed FIGI.
+type OrderbookEvent struct {
+	FIGI  string       `json:"figi"`
+	Depth int          `json:"depth"`
+	Bids  [][2]float64 `json:"bids"`
+	Asks  [][2]float64 `json:"asks"`
+}
+
+// InstrumentInfoEvent carries trading status changes for a subscribed FIGI.
+type InstrumentInfoEvent struct {
+	FIGI              string  `json:"figi"`
+	TradeStatus       string  `json:"trade_status"`
+	MinPriceIncrement float64 `json:"min_price_increment"`
+	Lot               int     `json:"lot"`
+	AccruedInterest   float64 `json:"accrued_interest,omitempty"`
+}
+
+// StreamingEvent is a tagged union of the three event kinds the streaming
+// connection can deliver. Exactly one field is non-nil.
+type StreamingEvent struct {
+	Candle         *CandleEvent
+	Orderbook      *OrderbookEvent
+	InstrumentInfo *InstrumentInfoEvent
+}
+
+// StreamingEventHandler receives every decoded event seen by Run.
+type StreamingEventHandler func(StreamingEvent)
+
+type incomingEvent struct {
+	Event   EventType       `json:"event"`
+	Time    time.Time       `json:"time"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+type outgoingEvent struct {
+	Event string `json:"event"`
+	Figi  string `json:"figi"`
+}
+
+type outgoingCandleEvent struct {
+	outgoingEvent
+	Interval CandleInterval `json:"interval"`
+}
+
+type outgoingOrderbookEvent struct {
+	outgoingEvent
+	Depth int `json:"depth"`
+}
+
+// StreamingClient is a client for the Tinkoff OpenAPI market data streaming
+// endpoint. It keeps track of the active subscriptions so that it can
+// resubscribe automatically after a reconnect.
+type StreamingClient struct {
+	token  string
+	apiURL string
+
+	mu            sync.Mutex
+	conn          *websocket.Conn
+	subscriptions map[string]interface{}
+}
+
+func NewStreamingClient(token string) *StreamingClient {
+	return NewStreamingClientCustom(token, StreamingApiURL)
+}
+
+func NewStreamingClientCustom(token, apiURL string) *StreamingClient {
+	return &StreamingClient{
+		token:         token,
+		apiURL:        apiURL,
+		subscriptions: make(map[string]interface{}),
+	}
+}
+
+func (c *StreamingClient) SubscribeCandle(figi string, interval CandleInterval) error {
+	return c.subscribe("candle:"+figi+":"+string(interval), outgoingCandleEvent{
+		outgoingEvent: outgoingEvent{Event: "candle:subscribe", Figi: figi},
+		Interval:      interval,
+	})
+}
+
+func (c *StreamingClient) UnsubscribeCandle(figi string, interval CandleInterval) error {
+	return c.unsubscribe("candle:"+figi+":"+string(interval), outgoingCandleEvent{
+		outgoingEvent: outgoingEvent{Event: "candle:unsubscribe", Figi: figi},
+		Interval:      interval,
+	})
+}
+
+func (c *StreamingClient) SubscribeOrderbook(figi string, depth int) error {
+	return c.subscribe("orderbook:"+figi, outgoingOrderbookEvent{
+		outgoingEvent: outgoingEvent{Event: "orderbook:subscribe", Figi: figi},
+		Depth:         depth,
+	})
+}
+
+func (c *StreamingClient) UnsubscribeOrderbook(figi string) error {
+	return c.unsubscribe("orderbook:"+figi, outgoingOrderbookEvent{
+		outgoingEvent: outgoingEvent{Event: "orderbook:unsubscribe", Figi: figi},
+	})
+}
+
+func (c *StreamingClient) SubscribeInstrumentInfo(figi string) error {
+	return c.subscribe("instrument_info:"+figi, outgoingEvent{
+		Event: "instrument_info:subscribe", Figi: figi,
+	})
+}
+
+func (c *StreamingClient) UnsubscribeInstrumentInfo(figi string) error {
+	return c.unsubscribe("instrument_info:"+figi, outgoingEvent{
+		Event: "instrument_info:unsubscribe", Figi: figi,
+	})
+}
+
+func (c *StreamingClient) subscribe(key string, payload interface{}) error {
+	c.mu.Lock()
+	c.subscriptions[key] = payload
+	conn := c.conn
+	c.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+	return c.send(conn, payload)
+}
+
+func (c *StreamingClient) unsubscribe(key string, payload interface{}) error {
+	c.mu.Lock()
+	delete(c.subscriptions, key)
+	conn := c.conn
+	c.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+	return c.send(conn, payload)
+}
+
+func (c *StreamingClient) send(conn *websocket.Conn, payload interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := conn.WriteJSON(payload); err != nil {
+		return errors.Wrap(err, "can't write to streaming connection")
+	}
+	return nil
+}
+
+// Run connects to the streaming endpoint and fans decoded events into
+// handler until ctx is canceled. On an unexpected disconnect it reconnects
+// with a backoff, resubscribes to everything it knew about, and keeps
+// going.
+func (c *StreamingClient) Run(ctx context.Context, handler StreamingEventHandler) error {
+	backoff := time.Second
+	const maxBackoff = 30 * time.Second
+
+	for {
+		err := c.runOnce(ctx, handler)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err == nil {
+			backoff = time.Second
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+func (c *StreamingClient) runOnce(ctx context.Context, handler StreamingEventHandler) error {
+	header := make(map[string][]string)
+	header["Authorization"] = []string{"Bearer " + c.token}
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, c.apiURL, header)
+	if err != nil {
+		return errors.Wrap(err, "can't dial streaming endpoint")
+	}
+	defer conn.Close()
+
+	c.mu.Lock()
+	c.conn = conn
+	subs := make([]interface{}, 0, len(c.subscriptions))
+	for _, payload := range c.subscriptions {
+		subs = append(subs, payload)
+	}
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		if c.conn == conn {
+			c.conn = nil
+		}
+		c.mu.Unlock()
+	}()
+
+	for _, payload := range subs {
+		if err := c.send(conn, payload); err != nil {
+			return err
+		}
+	}
+
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * pingPeriod))
+	})
+	if err := conn.SetReadDeadline(time.Now().Add(2 * pingPeriod)); err != nil {
+		return errors.Wrap(err, "can't set read deadline")
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go c.pingLoop(conn, done)
+	go closeOnContextDone(ctx, conn, done)
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return errors.Wrap(err, "can't read from streaming connection")
+		}
+
+		event, err := decodeEvent(message)
+		if err != nil {
+			return err
+		}
+		if event != nil {
+			handler(*event)
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+func (c *StreamingClient) pingLoop(conn *websocket.Conn, done <-chan struct{}) {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			err := conn.WriteMessage(websocket.PingMessage, nil)
+			c.mu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// closeOnContextDone closes conn as soon as ctx is canceled, unblocking a
+// pending ReadMessage so runOnce can return promptly instead of waiting for
+// the next pong to push the read deadline out further.
+func closeOnContextDone(ctx context.Context, conn *websocket.Conn, done <-chan struct{}) {
+	select {
+	case <-done:
+	case <-ctx.Done():
+		conn.Close()
+	}
+}
+
+func decodeEvent(message []byte) (*StreamingEvent, error) {
+	var in incomingEvent
+	if err := json.Unmarshal(message, &in); err != nil {
+		return nil, errors.Wrapf(err, "can't unmarshal streaming event, message=%s", message)
+	}
+
+	switch in.Event {
+	case CandleEventType:
+		var payload CandleEvent
+		if err := json.Unmarshal(in.Payload, &payload); err != nil {
+			return nil, errors.Wrapf(err, "can't unmarshal candle event, message=%s", message)
+		}
+		return &StreamingEvent{Candle: &payload}, nil
+	case OrderbookEventType:
+		var payload OrderbookEvent
+		if err := json.Unmarshal(in.Payload, &payload); err != nil {
+			return nil, errors.Wrapf(err, "can't unmarshal orderbook event, message=%s", message)
+		}
+		return &StreamingEvent{Orderbook: &payload}, nil
+	case InstrumentInfoEventType:
+		var payload InstrumentInfoEvent
+		if err := json.Unmarshal(in.Payload, &payload); err != nil {
+			return nil, errors.Wrapf(err, "can't unmarshal instrument info event, message=%s", message)
+		}
+		return &StreamingEvent{InstrumentInfo: &payload}, nil
+	default:
+		// Unknown or service event (e.g. error/heartbeat) - ignore it.
+		return nil, nil
+	}
+}
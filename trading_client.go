@@ -2,6 +2,7 @@ package sdk
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -18,34 +19,89 @@ const TradingApiURL = "https://api-invest.tinkoff.ru/openapi"
 var ErrNotFound = errors.New("Not found")
 
 type TradingClient struct {
-	httpClient *http.Client
-	token      string
-	apiURL     string
+	httpClient  *http.Client
+	token       string
+	apiURL      string
+	userAgent   string
+	retryPolicy RetryPolicy
+	rateLimiter *RateLimiter
 }
 
-func NewTradingClient(token string) *TradingClient {
-	return NewTradingClientCustom(token, TradingApiURL)
+// Option customizes a TradingClient at construction time.
+type Option func(*TradingClient)
+
+// WithHTTPClient replaces the default *http.Client used for all requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *TradingClient) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout sets a timeout on the client's underlying *http.Client. Callers
+// that need finer-grained control should instead pass a deadline/cancel via
+// the context given to each call.
+//
+// This clones the current *http.Client rather than mutating it in place, so
+// combining WithTimeout with a caller-supplied WithHTTPClient never changes
+// the Timeout on an *http.Client the caller still holds a reference to.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *TradingClient) {
+		cloned := *c.httpClient
+		cloned.Timeout = timeout
+		c.httpClient = &cloned
+	}
 }
 
-func NewTradingClientCustom(token, apiURL string) *TradingClient {
-	return &TradingClient{
-		httpClient: &http.Client{
-			Timeout: 5 * time.Second,
-		},
-		token:  token,
-		apiURL: apiURL,
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *TradingClient) {
+		c.userAgent = userAgent
 	}
 }
 
-func (c *TradingClient) SearchInstrumentByFIGI(figi string) (Instrument, error) {
+// WithRetryPolicy enables automatic retries for failed requests. Without
+// this option the client never retries.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *TradingClient) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithRateLimiter shares a RateLimiter across every request made by this
+// client, throttling callers before they hit the API's own limits.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *TradingClient) {
+		c.rateLimiter = limiter
+	}
+}
+
+func NewTradingClient(token string, opts ...Option) *TradingClient {
+	return NewTradingClientCustom(token, TradingApiURL, opts...)
+}
+
+func NewTradingClientCustom(token, apiURL string, opts ...Option) *TradingClient {
+	c := &TradingClient{
+		httpClient: &http.Client{},
+		token:      token,
+		apiURL:     apiURL,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *TradingClient) SearchInstrumentByFIGI(ctx context.Context, figi string) (Instrument, error) {
 	path := c.apiURL + "/market/search/by-figi?figi=" + figi
 
-	req, err := c.newRequest(http.MethodGet, path, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return Instrument{}, err
 	}
 
-	respBody, err := c.doRequest(req)
+	respBody, err := c.doRequest(req, true)
 	if err != nil {
 		return Instrument{}, err
 	}
@@ -62,43 +118,43 @@ func (c *TradingClient) SearchInstrumentByFIGI(figi string) (Instrument, error)
 	return resp.Payload, nil
 }
 
-func (c *TradingClient) SearchInstrumentByTicker(ticker string) ([]Instrument, error) {
+func (c *TradingClient) SearchInstrumentByTicker(ctx context.Context, ticker string) ([]Instrument, error) {
 	path := c.apiURL + "/market/search/by-ticker?ticker=" + ticker
 
-	return c.instruments(path)
+	return c.instruments(ctx, path)
 }
 
-func (c *TradingClient) Currencies() ([]Instrument, error) {
+func (c *TradingClient) Currencies(ctx context.Context) ([]Instrument, error) {
 	path := c.apiURL + "/market/currencies"
 
-	return c.instruments(path)
+	return c.instruments(ctx, path)
 }
 
-func (c *TradingClient) ETFs() ([]Instrument, error) {
+func (c *TradingClient) ETFs(ctx context.Context) ([]Instrument, error) {
 	path := c.apiURL + "/market/etfs"
 
-	return c.instruments(path)
+	return c.instruments(ctx, path)
 }
 
-func (c *TradingClient) Bonds() ([]Instrument, error) {
+func (c *TradingClient) Bonds(ctx context.Context) ([]Instrument, error) {
 	path := c.apiURL + "/market/bonds"
 
-	return c.instruments(path)
+	return c.instruments(ctx, path)
 }
 
-func (c *TradingClient) Stocks() ([]Instrument, error) {
+func (c *TradingClient) Stocks(ctx context.Context) ([]Instrument, error) {
 	path := c.apiURL + "/market/stocks"
 
-	return c.instruments(path)
+	return c.instruments(ctx, path)
 }
 
-func (c *TradingClient) instruments(path string) ([]Instrument, error) {
-	req, err := c.newRequest(http.MethodGet, path, nil)
+func (c *TradingClient) instruments(ctx context.Context, path string) ([]Instrument, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	respBody, err := c.doRequest(req)
+	respBody, err := c.doRequest(req, true)
 	if err != nil {
 		return nil, err
 	}
@@ -117,7 +173,7 @@ func (c *TradingClient) instruments(path string) ([]Instrument, error) {
 	return resp.Payload.Instruments, nil
 }
 
-func (c *TradingClient) Operations(from time.Time, interval OperationInterval, figi string) ([]Operation, error) {
+func (c *TradingClient) Operations(ctx context.Context, from time.Time, interval OperationInterval, figi string, opts ...CallOption) ([]Operation, error) {
 	q := url.Values{
 		"from":     []string{from.Format(time.RFC3339)},
 		"interval": []string{string(interval)},
@@ -126,14 +182,14 @@ func (c *TradingClient) Operations(from time.Time, interval OperationInterval, f
 		q.Set("figi", figi)
 	}
 
-	path := c.apiURL + "/operations?" + q.Encode()
+	path := withBrokerAccount(c.apiURL+"/operations?"+q.Encode(), applyCallOptions(opts))
 
-	req, err := c.newRequest(http.MethodGet, path, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	respBody, err := c.doRequest(req)
+	respBody, err := c.doRequest(req, true)
 	if err != nil {
 		return nil, err
 	}
@@ -150,13 +206,13 @@ func (c *TradingClient) Operations(from time.Time, interval OperationInterval, f
 	return resp.Payload, nil
 }
 
-func (c *TradingClient) Portfolio() (Portfolio, error) {
-	positions, err := c.PositionsPortfolio()
+func (c *TradingClient) Portfolio(ctx context.Context, opts ...CallOption) (Portfolio, error) {
+	positions, err := c.PositionsPortfolio(ctx, opts...)
 	if err != nil {
 		return Portfolio{}, err
 	}
 
-	currencies, err := c.CurrenciesPortfolio()
+	currencies, err := c.CurrenciesPortfolio(ctx, opts...)
 	if err != nil {
 		return Portfolio{}, err
 	}
@@ -167,15 +223,15 @@ func (c *TradingClient) Portfolio() (Portfolio, error) {
 	}, nil
 }
 
-func (c *TradingClient) PositionsPortfolio() ([]PositionBalance, error) {
-	path := c.apiURL + "/portfolio"
+func (c *TradingClient) PositionsPortfolio(ctx context.Context, opts ...CallOption) ([]PositionBalance, error) {
+	path := withBrokerAccount(c.apiURL+"/portfolio", applyCallOptions(opts))
 
-	req, err := c.newRequest(http.MethodGet, path, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	respBody, err := c.doRequest(req)
+	respBody, err := c.doRequest(req, true)
 	if err != nil {
 		return nil, err
 	}
@@ -194,15 +250,15 @@ func (c *TradingClient) PositionsPortfolio() ([]PositionBalance, error) {
 	return resp.Payload.Positions, nil
 }
 
-func (c *TradingClient) CurrenciesPortfolio() ([]CurrencyBalance, error) {
-	path := c.apiURL + "/portfolio/currencies"
+func (c *TradingClient) CurrenciesPortfolio(ctx context.Context, opts ...CallOption) ([]CurrencyBalance, error) {
+	path := withBrokerAccount(c.apiURL+"/portfolio/currencies", applyCallOptions(opts))
 
-	req, err := c.newRequest(http.MethodGet, path, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	respBody, err := c.doRequest(req)
+	respBody, err := c.doRequest(req, true)
 	if err != nil {
 		return nil, err
 	}
@@ -221,14 +277,16 @@ func (c *TradingClient) CurrenciesPortfolio() ([]CurrencyBalance, error) {
 	return resp.Payload.Currencies, nil
 }
 
-func (c *TradingClient) OrderCancel(id string) error {
-	path := c.apiURL + "/orders/cancel?orderId=" + id
+func (c *TradingClient) OrderCancel(ctx context.Context, id string, opts ...CallOption) error {
+	path := withBrokerAccount(c.apiURL+"/orders/cancel?orderId="+id, applyCallOptions(opts))
 
-	return c.postJSONThrow(path, nil)
+	// Canceling an already-canceled order is a no-op on the API side, so
+	// unlike order placement this is safe to retry.
+	return c.postJSONThrow(ctx, path, nil, true)
 }
 
-func (c *TradingClient) LimitOrder(figi string, lots int, operation OperationType, price float64) (PlacedLimitOrder, error) {
-	path := c.apiURL + "/orders/limit-order?figi=" + figi
+func (c *TradingClient) LimitOrder(ctx context.Context, figi string, lots int, operation OperationType, price float64, opts ...CallOption) (PlacedLimitOrder, error) {
+	path := withBrokerAccount(c.apiURL+"/orders/limit-order?figi="+figi, applyCallOptions(opts))
 
 	payload := struct {
 		Lots      int           `json:"lots"`
@@ -241,12 +299,12 @@ func (c *TradingClient) LimitOrder(figi string, lots int, operation OperationTyp
 		return PlacedLimitOrder{}, errors.Errorf("can't marshal request to %s body=%+v", path, payload)
 	}
 
-	req, err := c.newRequest(http.MethodPost, path, bytes.NewReader(bb))
+	req, err := c.newRequest(ctx, http.MethodPost, path, bytes.NewReader(bb))
 	if err != nil {
 		return PlacedLimitOrder{}, err
 	}
 
-	respBody, err := c.doRequest(req)
+	respBody, err := c.doRequest(req, false)
 	if err != nil {
 		return PlacedLimitOrder{}, err
 	}
@@ -263,15 +321,15 @@ func (c *TradingClient) LimitOrder(figi string, lots int, operation OperationTyp
 	return resp.Payload, nil
 }
 
-func (c *TradingClient) Orders() ([]Order, error) {
-	path := c.apiURL + "/orders"
+func (c *TradingClient) Orders(ctx context.Context, opts ...CallOption) ([]Order, error) {
+	path := withBrokerAccount(c.apiURL+"/orders", applyCallOptions(opts))
 
-	req, err := c.newRequest(http.MethodGet, path, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	respBody, err := c.doRequest(req)
+	respBody, err := c.doRequest(req, true)
 	if err != nil {
 		return nil, err
 	}
@@ -288,7 +346,7 @@ func (c *TradingClient) Orders() ([]Order, error) {
 	return resp.Payload, nil
 }
 
-func (c *TradingClient) postJSONThrow(url string, body interface{}) error {
+func (c *TradingClient) postJSONThrow(ctx context.Context, url string, body interface{}, retryable bool) error {
 	var bb []byte
 	var err error
 
@@ -299,52 +357,111 @@ func (c *TradingClient) postJSONThrow(url string, body interface{}) error {
 		}
 	}
 
-	req, err := c.newRequest(http.MethodPost, url, bytes.NewReader(bb))
+	req, err := c.newRequest(ctx, http.MethodPost, url, bytes.NewReader(bb))
 	if err != nil {
 		return err
 	}
 
-	_, err = c.doRequest(req)
+	_, err = c.doRequest(req, retryable)
 	return err
 }
 
-func (c *TradingClient) newRequest(method, url string, body io.Reader) (*http.Request, error) {
-	req, err := http.NewRequest(method, url, body)
+func (c *TradingClient) newRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, errors.Errorf("can't create http request to %s", url)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.token)
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
 
 	return req, nil
 }
 
-func (c *TradingClient) doRequest(req *http.Request) ([]byte, error) {
+// doRequest sends req, retrying per c.retryPolicy when retryable is true and
+// the response is one of the policy's retryable statuses. GET-like reads
+// pass retryable=true; calls that aren't safe to repeat blindly (e.g.
+// placing an order) pass false.
+func (c *TradingClient) doRequest(req *http.Request, retryable bool) ([]byte, error) {
+	attempts := c.retryPolicy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 {
+			attemptReq = req.Clone(req.Context())
+			if req.GetBody != nil {
+				rewound, err := req.GetBody()
+				if err != nil {
+					return nil, errors.Wrapf(err, "can't rewind request body to %s", req.URL.RawPath)
+				}
+				attemptReq.Body = ioutil.NopCloser(rewound)
+			}
+		}
+
+		body, status, retryAfter, err := c.do(attemptReq)
+		if err == nil {
+			return body, nil
+		}
+
+		lastAttempt := attempt == attempts-1
+		if !retryable || lastAttempt || !c.retryPolicy.retryable(status) {
+			if status == http.StatusTooManyRequests {
+				if tradingError, ok := err.(TradingError); ok {
+					return nil, &ErrRateLimited{TradingError: tradingError}
+				}
+			}
+			return nil, err
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(c.retryPolicy.delay(attempt, retryAfter)):
+		}
+	}
+}
+
+// do performs a single attempt, returning the response body on success or
+// the decoded error (plus the status code and any Retry-After duration) on
+// failure.
+func (c *TradingClient) do(req *http.Request) ([]byte, int, time.Duration, error) {
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrapf(err, "can't do request to %s", req.URL.RawPath)
+		return nil, 0, 0, errors.Wrapf(err, "can't do request to %s", req.URL.RawPath)
 	}
 	defer resp.Body.Close()
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, errors.Wrapf(err, "can't read response body to %s", req.URL.RawPath)
+		return nil, resp.StatusCode, 0, errors.Wrapf(err, "can't read response body to %s", req.URL.RawPath)
 	}
 
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+
 	switch resp.StatusCode {
 	case http.StatusOK:
+		return body, resp.StatusCode, 0, nil
 	case http.StatusNotFound:
-		return nil, ErrNotFound
+		return nil, resp.StatusCode, retryAfter, ErrNotFound
 	default:
 		var tradingError TradingError
 		if err := json.Unmarshal(body, &tradingError); err == nil {
-			return nil, tradingError
+			return nil, resp.StatusCode, retryAfter, tradingError
 		}
-		return nil, errors.Errorf("bad response to %s code=%d, body=%s", req.URL.RawPath, resp.StatusCode, body)
+		return nil, resp.StatusCode, retryAfter, errors.Errorf("bad response to %s code=%d, body=%s", req.URL.RawPath, resp.StatusCode, body)
 	}
-
-	return body, nil
 }
 
 type TradingError struct {
@@ -365,4 +482,4 @@ func (t TradingError) Error() string {
 
 func (t TradingError) NotEnoughBalance() bool {
 	return t.Payload.Code == "NOT_ENOUGH_BALANCE"
-}
\ No newline at end of file
+}